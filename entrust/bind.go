@@ -18,12 +18,14 @@ package entrust
 
 import (
 	"context"
+	"encoding/json"
 	"math/big"
 
 	"github.com/trust-tech/go-trustmachine"
 	"github.com/trust-tech/go-trustmachine/common"
 	"github.com/trust-tech/go-trustmachine/common/hexutil"
 	"github.com/trust-tech/go-trustmachine/core/types"
+	"github.com/trust-tech/go-trustmachine/event"
 	"github.com/trust-tech/go-trustmachine/internal/entrustapi"
 	"github.com/trust-tech/go-trustmachine/rlp"
 	"github.com/trust-tech/go-trustmachine/rpc"
@@ -37,18 +39,22 @@ import (
 // object. These should be rewritten to internal Go method calls when the Go API
 // is refactored to support a clean library use.
 type ContractBackend struct {
-	eapi  *entrustapi.PublicTrustmachineAPI        // Wrapper around the Trustmachine object to access metadata
-	bcapi *entrustapi.PublicBlockChainAPI      // Wrapper around the blockchain to access chain data
-	txapi *entrustapi.PublicTransactionPoolAPI // Wrapper around the transaction pool to access transaction data
+	eapi      *entrustapi.PublicTrustmachineAPI    // Wrapper around the Trustmachine object to access metadata
+	bcapi     *entrustapi.PublicBlockChainAPI      // Wrapper around the blockchain to access chain data
+	txapi     *entrustapi.PublicTransactionPoolAPI // Wrapper around the transaction pool to access transaction data
+	filterapi *entrustapi.PublicFilterAPI          // Wrapper around the log filter API to service FilterLogs
+	events    *entrustapi.EventSystem              // Live feed used to back log subscriptions
 }
 
 // NewContractBackend creates a new native contract backend using an existing
 // Trustuem object.
 func NewContractBackend(apiBackend entrustapi.Backend) *ContractBackend {
 	return &ContractBackend{
-		eapi:  entrustapi.NewPublicTrustmachineAPI(apiBackend),
-		bcapi: entrustapi.NewPublicBlockChainAPI(apiBackend),
-		txapi: entrustapi.NewPublicTransactionPoolAPI(apiBackend, new(entrustapi.AddrLocker)),
+		eapi:      entrustapi.NewPublicTrustmachineAPI(apiBackend),
+		bcapi:     entrustapi.NewPublicBlockChainAPI(apiBackend),
+		txapi:     entrustapi.NewPublicTransactionPoolAPI(apiBackend, new(entrustapi.AddrLocker)),
+		filterapi: entrustapi.NewPublicFilterAPI(apiBackend, false),
+		events:    entrustapi.NewEventSystem(apiBackend),
 	}
 }
 
@@ -66,34 +72,119 @@ func (b *ContractBackend) PendingCodeAt(ctx context.Context, contract common.Add
 // call with the specified data as the input. The pending flag requests execution
 // against the pending block, not the stable head of the chain.
 func (b *ContractBackend) CallContract(ctx context.Context, msg trustmachine.CallMsg, blockNum *big.Int) ([]byte, error) {
-	out, err := b.bcapi.Call(ctx, toCallArgs(msg), toBlockNumber(blockNum))
-	return out, err
+	args, err := toCallArgs(msg)
+	if err != nil {
+		return nil, err
+	}
+	return b.bcapi.Call(ctx, args, toBlockNumber(blockNum))
 }
 
 // ContractCall implements bind.ContractCaller executing an Trustmachine contract
 // call with the specified data as the input. The pending flag requests execution
 // against the pending block, not the stable head of the chain.
 func (b *ContractBackend) PendingCallContract(ctx context.Context, msg trustmachine.CallMsg) ([]byte, error) {
-	out, err := b.bcapi.Call(ctx, toCallArgs(msg), rpc.PendingBlockNumber)
-	return out, err
+	args, err := toCallArgs(msg)
+	if err != nil {
+		return nil, err
+	}
+	return b.bcapi.Call(ctx, args, rpc.PendingBlockNumber)
+}
+
+// FilterLogs implements bind.ContractFilterer executing a log filter
+// operation, blocking during execution and returning all the results in one
+// batch.
+func (b *ContractBackend) FilterLogs(ctx context.Context, query trustmachine.FilterQuery) ([]types.Log, error) {
+	logs, err := b.filterapi.GetLogs(ctx, toFilterCriteria(query))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]types.Log, len(logs))
+	for i, log := range logs {
+		res[i] = *log
+	}
+	return res, nil
+}
+
+// SubscribeFilterLogs implements bind.ContractFilterer creating a background
+// log filtering operation, returning a subscription immediately, which can
+// be used to stream the found events.
+func (b *ContractBackend) SubscribeFilterLogs(ctx context.Context, query trustmachine.FilterQuery, ch chan<- types.Log) (trustmachine.Subscription, error) {
+	sink := make(chan []*types.Log)
+	sub, err := b.events.SubscribeLogs(toFilterCriteria(query), sink)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case logs := <-sink:
+				for _, log := range logs {
+					select {
+					case ch <- *log:
+					case <-quit:
+						return nil
+					}
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// toFilterCriteria converts a trustmachine.FilterQuery, the bind-facing
+// address/topic/block-range filter, into the entrustapi.FilterCriteria
+// consumed by the filter API.
+func toFilterCriteria(query trustmachine.FilterQuery) entrustapi.FilterCriteria {
+	return entrustapi.FilterCriteria{
+		FromBlock: query.FromBlock,
+		ToBlock:   query.ToBlock,
+		Addresses: query.Addresses,
+		Topics:    query.Topics,
+	}
 }
 
-func toCallArgs(msg trustmachine.CallMsg) entrustapi.CallArgs {
-	args := entrustapi.CallArgs{
-		To:   msg.To,
-		From: msg.From,
-		Data: msg.Data,
+// toCallArgs converts a trustmachine.CallMsg into entrustapi.CallArgs by
+// round-tripping it through the CallArgs JSON codec, the same encoding used
+// when a call crosses an RPC boundary. CallArgs keeps Gas/GasPrice/Value as
+// pointers through the round trip, so a field the caller left nil is
+// marshaled as absent and decodes back to nil rather than to a zero value.
+// Only once decoded are the fields copied, nil-checked, into
+// entrustapi.CallArgs's value-typed big-ints.
+func toCallArgs(msg trustmachine.CallMsg) (entrustapi.CallArgs, error) {
+	enc, err := json.Marshal(&CallArgs{
+		From:     msg.From,
+		To:       msg.To,
+		Gas:      msg.Gas,
+		GasPrice: msg.GasPrice,
+		Value:    msg.Value,
+		Data:     msg.Data,
+	})
+	if err != nil {
+		return entrustapi.CallArgs{}, err
 	}
-	if msg.Gas != nil {
-		args.Gas = hexutil.Big(*msg.Gas)
+	var args CallArgs
+	if err := json.Unmarshal(enc, &args); err != nil {
+		return entrustapi.CallArgs{}, err
 	}
-	if msg.GasPrice != nil {
-		args.GasPrice = hexutil.Big(*msg.GasPrice)
+	out := entrustapi.CallArgs{
+		From: args.From,
+		To:   args.To,
+		Data: args.Data,
 	}
-	if msg.Value != nil {
-		args.Value = hexutil.Big(*msg.Value)
+	if args.Gas != nil {
+		out.Gas = hexutil.Big(*args.Gas)
 	}
-	return args
+	if args.GasPrice != nil {
+		out.GasPrice = hexutil.Big(*args.GasPrice)
+	}
+	if args.Value != nil {
+		out.Value = hexutil.Big(*args.Value)
+	}
+	return out, nil
 }
 
 func toBlockNumber(num *big.Int) rpc.BlockNumber {
@@ -125,8 +216,15 @@ func (b *ContractBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error)
 // requirement as other transactions may be added or removed by miners, but it
 // should provide a basis for setting a reasonable default.
 func (b *ContractBackend) EstimateGas(ctx context.Context, msg trustmachine.CallMsg) (*big.Int, error) {
-	out, err := b.bcapi.EstimateGas(ctx, toCallArgs(msg))
-	return out.ToInt(), err
+	args, err := toCallArgs(msg)
+	if err != nil {
+		return nil, err
+	}
+	out, err := b.bcapi.EstimateGas(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return out.ToInt(), nil
 }
 
 // SendTransaction implements bind.ContractTransactor injects the transaction
@@ -136,3 +234,43 @@ func (b *ContractBackend) SendTransaction(ctx context.Context, tx *types.Transac
 	_, err := b.txapi.SendRawTransaction(ctx, raw)
 	return err
 }
+
+// TransactionReceipt implements bind.DeployBackend retrieving the receipt
+// for a mined transaction, so bind.WaitMined and bind.WaitDeployed can be
+// used against an in-process backend the same way they are against an RPC
+// client. It returns the raw receipt unexamined, the same contract other
+// DeployBackend implementations (e.g. ethclient.Client) honor; surfacing a
+// revert via the receipt status, or a failed deploy via empty code at the
+// contract address, is left to bind.WaitMined/bind.WaitDeployed, which
+// already inspect the receipt this method returns.
+//
+// Rather than have the caller poll on a ticker, it blocks here and
+// subscribes to the chain's header feed before taking its first look at the
+// receipt, so a block imported concurrently with the call is never missed,
+// and wakes again on every later import instead of on a fixed interval.
+func (b *ContractBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	heads := make(chan *types.Header)
+	sub, err := b.events.SubscribeNewHeads(heads)
+	if err != nil {
+		return nil, err
+	}
+	defer sub.Unsubscribe()
+
+	if receipt, err := b.bcapi.GetTransactionReceipt(ctx, txHash); err != nil || receipt != nil {
+		return receipt, err
+	}
+
+	for {
+		select {
+		case <-heads:
+			receipt, err := b.bcapi.GetTransactionReceipt(ctx, txHash)
+			if err != nil || receipt != nil {
+				return receipt, err
+			}
+		case err := <-sub.Err():
+			return nil, err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2015 The go-trustmachine Authors
+// This file is part of the go-trustmachine library.
+//
+// The go-trustmachine library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-trustmachine library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-trustmachine library. If not, see <http://www.gnu.org/licenses/>.
+
+package entrust
+
+import (
+	"math/big"
+
+	"github.com/trust-tech/go-trustmachine/common"
+	"github.com/trust-tech/go-trustmachine/common/hexutil"
+)
+
+//go:generate gencodec -type CallArgs -field-override callArgsMarshaling -out gen_callargs_json.go
+
+// CallArgs is the canonical wire form of a call/estimate-gas request, the
+// same encoding an RPC client would send. Gas, GasPrice and Value stay
+// pointers through JSON so a field the caller never set is marshaled as
+// absent rather than as the zero value, and decodes back to nil instead of
+// a false zero.
+type CallArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to,omitempty"`
+	Gas      *big.Int        `json:"gas,omitempty"`
+	GasPrice *big.Int        `json:"gasPrice,omitempty"`
+	Value    *big.Int        `json:"value,omitempty"`
+	Data     []byte          `json:"data"`
+}
+
+// callArgsMarshaling is the field type overrides for CallArgs JSON encoding.
+type callArgsMarshaling struct {
+	Gas      *hexutil.Big
+	GasPrice *hexutil.Big
+	Value    *hexutil.Big
+	Data     hexutil.Bytes
+}
@@ -81,15 +81,25 @@ type trg struct {
 	pthLkp map[string]*watched
 	// t is a platform dependent implementation of trigger.
 	t trigger
+	// rec maps roots registered via RecursiveWatch to the events requested
+	// for them, so that newly created subdirectories discovered underneath
+	// one of them get a watch installed automatically.
+	rec map[string]Event
+	// coalesce, if non-nil, debounces bursty events before they reach c.
+	// Installed via the WithCoalesce Option or CoalescePath.
+	coalesce *coalescer
 }
 
 // newWatcher returns new watcher's implementation.
-func newWatcher(c chan<- EventInfo) watcher {
+func newWatcher(c chan<- EventInfo, opts ...Option) watcher {
 	t := &trg{
 		s:      make(chan struct{}, 1),
 		pthLkp: make(map[string]*watched, 0),
 		c:      c,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
 	t.t = newTrigger(t.pthLkp)
 	if err := t.t.Init(); err != nil {
 		panic(err)
@@ -98,6 +108,15 @@ func newWatcher(c chan<- EventInfo) watcher {
 	return t
 }
 
+// NewWatcher is like the package-level Watch/NewTree entry points, but
+// returns the concrete *trg instead of the narrower watcher interface, so a
+// caller that wants WithCoalesce's burst debouncing -- a directory a
+// compiler or a keystore rescans heavily, for example -- can reach
+// CoalescePath and Stats, which aren't part of that interface.
+func NewWatcher(c chan<- EventInfo, opts ...Option) *trg {
+	return newWatcher(c, opts...).(*trg)
+}
+
 // Close implements watcher.
 func (t *trg) Close() (err error) {
 	t.Lock()
@@ -121,8 +140,17 @@ func (t *trg) Close() (err error) {
 	return
 }
 
-// send reported events one by one through chan.
+// send reported events one by one through chan, or via the coalescer when
+// one is installed. t.coalesce is read under t.Lock since CoalescePath may
+// install it after the watcher has started running.
 func (t *trg) send(evn []event) {
+	t.Lock()
+	coalesce := t.coalesce
+	t.Unlock()
+	if coalesce != nil {
+		coalesce.push(evn)
+		return
+	}
 	for i := range evn {
 		t.c <- &evn[i]
 	}
@@ -306,10 +334,12 @@ func (t *trg) dir(w *watched, n interface{}, e, ge Event) (evn []event) {
 				}
 			}
 		}
+		delete(t.rec, w.p)
 		t.t.Del(w)
 		return
 	}
 	if (ge & not2nat[Write]) != 0 {
+		_, recE, recursed := t.recursiveRoot(w.p)
 		switch err := t.walk(w.p, func(fi os.FileInfo) error {
 			p := filepath.Join(w.p, fi.Name())
 			switch err := t.singlewatch(p, w.eDir, ndir, fi); {
@@ -322,6 +352,11 @@ func (t *trg) dir(w *watched, n interface{}, e, ge Event) (evn []event) {
 				evn = append(evn, event{p, Create, fi.IsDir(), n})
 			default:
 			}
+			if recursed && fi.IsDir() {
+				if err := t.watchTree(p, recE, make(map[uint64]struct{}), 0); err != nil {
+					dbgprintf("trg: recursive watch of new dir %q failed: %q", p, err)
+				}
+			}
 			return nil
 		}); {
 		case os.IsNotExist(err):
@@ -342,6 +377,87 @@ const (
 	both
 )
 
+// maxRecursiveDepth bounds how deep RecursiveWatch will descend into a
+// directory tree, guarding against pathological depth and symlink-induced
+// cycles that inode tracking alone could miss.
+const maxRecursiveDepth = 128
+
+// RecursiveWatch starts watching p and, if p is a directory, every
+// subdirectory found underneath it, now and as the tree grows: whenever a
+// new subdirectory is created inside a recursively watched tree, dir and
+// process automatically descend into it and install watches on it and its
+// contents.
+func (t *trg) RecursiveWatch(p string, e Event) error {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		t.Lock()
+		err = t.watch(p, e, fi)
+		t.Unlock()
+		return err
+	}
+	t.Lock()
+	defer t.Unlock()
+	if t.rec == nil {
+		t.rec = make(map[string]Event)
+	}
+	t.rec[p] = e
+	return t.watchTree(p, e, make(map[uint64]struct{}), 0)
+}
+
+// watchTree installs a watch on p and recurses into its subdirectories,
+// bailing out on excessive depth and on inodes already seen (a symlink or
+// bind-mount cycle) so a pathological tree cannot spin the walk forever.
+func (t *trg) watchTree(p string, e Event, seen map[uint64]struct{}, depth int) error {
+	if depth > maxRecursiveDepth {
+		dbgprintf("trg: %q exceeds max recursive watch depth (%d), not descending\n", p, maxRecursiveDepth)
+		return nil
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if ino, ok := inode(fi); ok {
+		if _, dup := seen[ino]; dup {
+			return nil
+		}
+		seen[ino] = struct{}{}
+	}
+	if err := t.watch(p, e, fi); err != nil {
+		return err
+	}
+	return t.walk(p, func(fi os.FileInfo) error {
+		if !fi.IsDir() {
+			return nil
+		}
+		return t.watchTree(filepath.Join(p, fi.Name()), e, seen, depth+1)
+	})
+}
+
+// recursiveRoot reports whether p falls under a tree registered via
+// RecursiveWatch, returning the registered root and the event mask it was
+// registered with.
+func (t *trg) recursiveRoot(p string) (string, Event, bool) {
+	for root, e := range t.rec {
+		if p == root || strings.HasPrefix(p, root+string(os.PathSeparator)) {
+			return root, e, true
+		}
+	}
+	return "", 0, false
+}
+
+// inode extracts the platform inode number from fi, used to detect cycles
+// while walking a directory tree.
+func inode(fi os.FileInfo) (uint64, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(st.Ino), true
+}
+
 // unwatch stops watching p file/directory.
 func (t *trg) singleunwatch(p string, direct mode) error {
 	w, ok := t.pthLkp[p]
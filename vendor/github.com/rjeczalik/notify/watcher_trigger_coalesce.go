@@ -0,0 +1,225 @@
+// Copyright (c) 2014-2015 The Notify Authors. All rights reserved.
+// Use of this source code is governed by the MIT license that can be
+// found in the LICENSE file.
+
+// +build darwin,kqueue dragonfly freebsd netbsd openbsd solaris
+
+// watcher_trigger_coalesce adds an optional debouncing stage between
+// trg.monitor and the user channel t.c: bursts of Write events landing on
+// the same path within a small window, and a Rename immediately followed by
+// a Create for the same inode, are collapsed into a single logical event
+// before delivery. This keeps a burst (a compiler rewriting many files, or
+// the keystore rescanning a large directory) from blocking the native
+// reader goroutine on a slow consumer.
+
+package notify
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// coalesceRingSize bounds the number of events a coalescer may hold pending
+// at once; once full, new events are dropped rather than growing memory
+// unboundedly during a sustained burst.
+const coalesceRingSize = 4096
+
+// Stats is a snapshot of a watcher's coalescer counters, reachable through
+// the Stats method alongside the existing dbgprintf hook.
+type Stats struct {
+	// Dropped counts events discarded because the coalescer's ring was full.
+	Dropped uint64
+	// Coalesced counts events merged into an already pending one.
+	Coalesced uint64
+	// Delivered counts events forwarded to the user channel.
+	Delivered uint64
+}
+
+// pendingEvent is an event held back by the coalescer, waiting for its
+// window to elapse before delivery.
+type pendingEvent struct {
+	evn   event
+	timer *time.Timer
+}
+
+// coalescer merges bursty events before they reach the user channel. It is
+// installed on a trg via WithCoalesce or CoalescePath.
+type coalescer struct {
+	sync.Mutex
+	window  time.Duration
+	byPath  map[string]time.Duration
+	out     chan<- EventInfo
+	pending map[string]*pendingEvent
+	ring    int // number of events currently pending, bounded by coalesceRingSize
+
+	dropped, coalesced, delivered uint64
+}
+
+func newCoalescer(window time.Duration, out chan<- EventInfo) *coalescer {
+	return &coalescer{
+		window:  window,
+		byPath:  make(map[string]time.Duration),
+		out:     out,
+		pending: make(map[string]*pendingEvent),
+	}
+}
+
+// push hands evn to the coalescer instead of the user channel directly.
+func (c *coalescer) push(evn []event) {
+	for i := range evn {
+		c.pushOne(&evn[i])
+	}
+}
+
+func (c *coalescer) pushOne(e *event) {
+	c.Lock()
+
+	window := c.window
+	if w, ok := c.byPath[e.path]; ok {
+		window = w
+	}
+
+	// A Rename is frequently followed, within the same burst, by a Create
+	// reporting the same path coming into existence again (e.g. an editor's
+	// atomic save). Hold the Rename briefly so a prompt Create for the same
+	// inode can absorb it. The key is scoped to the inode, never the parent
+	// directory, so an unrelated Create elsewhere in the same directory can't
+	// cancel it.
+	if e.event&Rename != 0 && window > 0 {
+		c.schedule(renameKey(e.path), e, window)
+		c.Unlock()
+		return
+	}
+	if e.event&Create != 0 {
+		key := renameKey(e.path)
+		if pe, ok := c.pending[key]; ok {
+			pe.timer.Stop()
+			delete(c.pending, key)
+			c.ring--
+			atomic.AddUint64(&c.coalesced, 1)
+		}
+	}
+
+	if pe, ok := c.pending[e.path]; ok {
+		pe.evn.event |= e.event
+		atomic.AddUint64(&c.coalesced, 1)
+		c.Unlock()
+		return
+	}
+	if window <= 0 {
+		c.Unlock()
+		c.deliver(e)
+		return
+	}
+	c.schedule(e.path, e, window)
+	c.Unlock()
+}
+
+// renameKey builds the pending map key under which a held-back Rename waits
+// for a matching Create. It is keyed on the file's inode when one can be
+// read, falling back to the full path (never just the parent directory) so
+// an unrelated event elsewhere in the same directory can't match it.
+func renameKey(path string) string {
+	if fi, err := os.Lstat(path); err == nil {
+		if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+			return "rename:ino:" + strconv.FormatUint(uint64(st.Ino), 10)
+		}
+	}
+	return "rename:path:" + path
+}
+
+// schedule holds e under key until window elapses, unless the ring is
+// already full, in which case e is dropped and counted.
+func (c *coalescer) schedule(key string, e *event, window time.Duration) {
+	if c.ring >= coalesceRingSize {
+		atomic.AddUint64(&c.dropped, 1)
+		dbgprintf("trg: coalesce ring full (%d), dropping event for %q\n", coalesceRingSize, e.path)
+		return
+	}
+	c.ring++
+	pe := &pendingEvent{evn: *e}
+	pe.timer = time.AfterFunc(window, func() { c.flush(key) })
+	c.pending[key] = pe
+}
+
+func (c *coalescer) flush(key string) {
+	c.Lock()
+	pe, ok := c.pending[key]
+	if ok {
+		delete(c.pending, key)
+		c.ring--
+	}
+	c.Unlock()
+	if !ok {
+		return
+	}
+	atomic.AddUint64(&c.delivered, 1)
+	c.out <- &pe.evn
+}
+
+// deliver sends e directly to the user channel, bypassing the pending map.
+// Called with c unlocked: the send can block on a slow consumer, and it must
+// not hold the lock pushOne and flush need to keep making progress on the
+// native reader goroutine.
+func (c *coalescer) deliver(e *event) {
+	c.out <- e
+	atomic.AddUint64(&c.delivered, 1)
+}
+
+func (c *coalescer) stats() Stats {
+	return Stats{
+		Dropped:   atomic.LoadUint64(&c.dropped),
+		Coalesced: atomic.LoadUint64(&c.coalesced),
+		Delivered: atomic.LoadUint64(&c.delivered),
+	}
+}
+
+// Option configures optional behavior of a watcher created by newWatcher.
+type Option func(*trg)
+
+// WithCoalesce enables coalescing: Write events landing on the same path
+// within window, and a Rename immediately followed by a Create for the same
+// inode, are collapsed into a single logical event. A window of zero
+// disables coalescing (the default).
+func WithCoalesce(window time.Duration) Option {
+	return func(t *trg) {
+		if t.coalesce == nil {
+			t.coalesce = newCoalescer(window, t.c)
+			return
+		}
+		t.coalesce.window = window
+	}
+}
+
+// CoalescePath overrides the coalescing window for a single path, taking
+// precedence over the watcher-wide window set via WithCoalesce. A window of
+// zero disables coalescing for that path.
+func (t *trg) CoalescePath(p string, window time.Duration) {
+	t.Lock()
+	if t.coalesce == nil {
+		t.coalesce = newCoalescer(0, t.c)
+	}
+	coalesce := t.coalesce
+	t.Unlock()
+	coalesce.Lock()
+	coalesce.byPath[p] = window
+	coalesce.Unlock()
+}
+
+// Stats reports the watcher's coalescer counters. It reads zero values if
+// neither WithCoalesce nor CoalescePath was ever used. t.coalesce is read
+// under t.Lock, mirroring
+// send, since CoalescePath may install it after the watcher has started.
+func (t *trg) Stats() Stats {
+	t.Lock()
+	coalesce := t.coalesce
+	t.Unlock()
+	if coalesce == nil {
+		return Stats{}
+	}
+	return coalesce.stats()
+}
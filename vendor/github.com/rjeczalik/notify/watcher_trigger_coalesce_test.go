@@ -0,0 +1,138 @@
+// Copyright (c) 2014-2015 The Notify Authors. All rights reserved.
+// Use of this source code is governed by the MIT license that can be
+// found in the LICENSE file.
+
+// +build darwin,kqueue dragonfly freebsd netbsd openbsd solaris
+
+package notify
+
+import (
+	"io/ioutil"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestCoalescerRingDrop checks that once coalesceRingSize events are
+// pending, further events are dropped and counted rather than growing the
+// pending map without bound.
+func TestCoalescerRingDrop(t *testing.T) {
+	out := make(chan EventInfo) // never drained: nothing here should deliver
+	c := newCoalescer(time.Hour, out)
+
+	for i := 0; i < coalesceRingSize+5; i++ {
+		c.pushOne(&event{path: "/p/" + strconv.Itoa(i), event: Write})
+	}
+
+	if c.ring != coalesceRingSize {
+		t.Fatalf("ring = %d, want %d", c.ring, coalesceRingSize)
+	}
+	if got := c.stats().Dropped; got != 5 {
+		t.Fatalf("Dropped = %d, want 5", got)
+	}
+}
+
+// TestCoalescerRenameCreateSameInode checks that a Create following a
+// pending Rename for the same inode cancels the Rename instead of
+// delivering both.
+func TestCoalescerRenameCreateSameInode(t *testing.T) {
+	f, err := ioutil.TempFile("", "notify-coalesce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	out := make(chan EventInfo, 1)
+	c := newCoalescer(time.Hour, out)
+
+	c.pushOne(&event{path: path, event: Rename})
+	if n := len(c.pending); n != 1 {
+		t.Fatalf("pending after Rename = %d, want 1", n)
+	}
+
+	c.pushOne(&event{path: path, event: Create})
+	if got := c.stats().Coalesced; got != 1 {
+		t.Fatalf("Coalesced = %d, want 1", got)
+	}
+
+	select {
+	case ev := <-out:
+		t.Fatalf("unexpected delivery: %v", ev)
+	default:
+	}
+}
+
+// TestCoalescerRenameCreateDifferentInode is the regression case for
+// keying the Rename/Create merge on the parent directory instead of the
+// inode: an unrelated Create for a different file in the same directory
+// must not cancel a pending Rename.
+func TestCoalescerRenameCreateDifferentInode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notify-coalesce-dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, err := ioutil.TempFile(dir, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+	b, err := ioutil.TempFile(dir, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Close()
+
+	out := make(chan EventInfo, 1)
+	c := newCoalescer(time.Hour, out)
+
+	c.pushOne(&event{path: a.Name(), event: Rename})
+	c.pushOne(&event{path: b.Name(), event: Create})
+
+	if got := c.stats().Coalesced; got != 0 {
+		t.Fatalf("Coalesced = %d, want 0 (unrelated Create must not cancel A's Rename)", got)
+	}
+	if _, ok := c.pending[renameKey(a.Name())]; !ok {
+		t.Fatal("A's pending Rename was dropped by an unrelated Create in the same directory")
+	}
+}
+
+// TestCoalescerTimerFlushDelivers checks that an event held back under a
+// non-zero window is eventually delivered once the window elapses.
+func TestCoalescerTimerFlushDelivers(t *testing.T) {
+	out := make(chan EventInfo, 1)
+	c := newCoalescer(10*time.Millisecond, out)
+
+	c.pushOne(&event{path: "/p/flush", event: Write})
+
+	select {
+	case <-out:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for coalescer to flush")
+	}
+	if got := c.stats().Delivered; got != 1 {
+		t.Fatalf("Delivered = %d, want 1", got)
+	}
+}
+
+// TestCoalescerZeroWindowDeliversImmediately checks that a zero window
+// bypasses the pending map entirely.
+func TestCoalescerZeroWindowDeliversImmediately(t *testing.T) {
+	out := make(chan EventInfo, 1)
+	c := newCoalescer(0, out)
+
+	c.pushOne(&event{path: "/p/now", event: Write})
+
+	select {
+	case <-out:
+	default:
+		t.Fatal("expected immediate delivery with a zero window")
+	}
+	if len(c.pending) != 0 {
+		t.Fatalf("pending = %d, want 0", len(c.pending))
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2014-2015 The Notify Authors. All rights reserved.
+// Use of this source code is governed by the MIT license that can be
+// found in the LICENSE file.
+
+// +build darwin,kqueue dragonfly freebsd netbsd openbsd solaris
+
+package notify
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// TestWatchTreeDepthBailout checks that watchTree refuses to descend past
+// maxRecursiveDepth without touching the filesystem, so a pathologically
+// deep tree can't spin the walk forever.
+func TestWatchTreeDepthBailout(t *testing.T) {
+	tr := &trg{pthLkp: make(map[string]*watched)}
+	// A path that does not exist would make os.Stat fail if watchTree got
+	// that far; reaching nil confirms the depth guard short-circuits first.
+	if err := tr.watchTree("/does/not/exist", 0, make(map[uint64]struct{}), maxRecursiveDepth+1); err != nil {
+		t.Fatalf("watchTree at excessive depth = %q, want nil", err)
+	}
+}
+
+// TestWatchTreeCycleBailout checks that watchTree stops descending into an
+// inode it has already seen in this walk (a symlink or bind-mount cycle)
+// instead of installing a duplicate watch.
+func TestWatchTreeCycleBailout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notify-cycle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fi, err := os.Stat(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ino, ok := inode(fi)
+	if !ok {
+		t.Fatal("inode: not available on this platform")
+	}
+
+	tr := &trg{pthLkp: make(map[string]*watched)}
+	seen := map[uint64]struct{}{ino: {}}
+	// dir's inode is already marked seen, so watchTree must return before
+	// calling t.watch, which would otherwise need a real trigger.
+	if err := tr.watchTree(dir, 0, seen, 0); err != nil {
+		t.Fatalf("watchTree on already-seen inode = %q, want nil", err)
+	}
+}